@@ -2,10 +2,29 @@ package e2e
 
 import (
 	"testing"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// hasEventWithReason reports whether the fieldSelector-selected events for a pod
+// include one with the given reason.
+func hasEventWithReason(namespace, name, reason string) (bool, error) {
+	events, err := f.KubeClient.CoreV1().Events(namespace).List(metav1.ListOptions{
+		FieldSelector: "involvedObject.name=" + name,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, e := range events.Items {
+		if e.Reason == reason {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // TestGetStatsSummary creates a pod having two containers and queries the /stats/summary endpoint of the virtual-kubelet.
 // It expects this endpoint to return stats for the current node, as well as for the aforementioned pod and each of its two containers.
 func TestGetStatsSummary(t *testing.T) {
@@ -58,6 +77,53 @@ func TestGetStatsSummary(t *testing.T) {
 	}
 }
 
+// TestGetResourceMetrics creates a pod having two containers and scrapes the
+// /metrics/resource endpoint of the virtual-kubelet. It expects the Prometheus
+// exposition format response to contain container-level samples for both containers.
+func TestGetResourceMetrics(t *testing.T) {
+	// Create a pod with prefix "nginx-3-" having two containers.
+	pod, err := f.CreatePod(f.CreateDummyPodObjectWithPrefix("nginx-3-", "bar", "baz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Delete the "nginx-3-X" pod after the test finishes.
+	defer func() {
+		if err := f.DeletePod(pod.Namespace, pod.Name); err != nil && !apierrors.IsNotFound(err) {
+			t.Error(err)
+		}
+	}()
+
+	// Wait for the "nginx-3-X" pod to be reported as running and ready.
+	if err := f.WaitUntilPodReady(pod.Namespace, pod.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	// Scrape the resource metrics from the provider.
+	metrics, err := f.GetResourceMetrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Make sure we've got per-container samples for the pod we created above.
+	desiredSampleCount := 2
+	currentSampleCount := 0
+	for _, mf := range metrics {
+		if mf.GetName() != "container_cpu_usage_seconds_total" {
+			continue
+		}
+		for _, m := range mf.Metric {
+			for _, l := range m.Label {
+				if l.GetName() == "pod" && l.GetValue() == pod.Name {
+					currentSampleCount++
+				}
+			}
+		}
+	}
+	if currentSampleCount != desiredSampleCount {
+		t.Fatalf("expected %d container samples, got %d", desiredSampleCount, currentSampleCount)
+	}
+}
+
 // TestPodLifecycle creates two pods and verifies that the provider has been asked to create them.
 // Then, it deletes one of the pods and verifies that the provider has been asked to delete it.
 // These verifications are made using the /stats/summary endpoint of the virtual-kubelet, by checking for the presence or absence of the pods.
@@ -101,6 +167,13 @@ func TestPodLifecycle(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Make sure virtual-kubelet recorded a ProviderCreated event for the created pod.
+	if ok, err := hasEventWithReason(pod0.Namespace, pod0.Name, "ProviderCreated"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatalf("expected a ProviderCreated event for pod %s/%s", pod0.Namespace, pod0.Name)
+	}
+
 	// Grab the stats from the provider.
 	stats, err := f.GetStatsSummary()
 	if err != nil {
@@ -124,6 +197,13 @@ func TestPodLifecycle(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Make sure virtual-kubelet recorded a ProviderDeleted event for the deleted pod.
+	if ok, err := hasEventWithReason(pod1.Namespace, pod1.Name, "ProviderDeleted"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatalf("expected a ProviderDeleted event for pod %s/%s", pod1.Namespace, pod1.Name)
+	}
+
 	// Grab the stats from the provider.
 	stats, err = f.GetStatsSummary()
 	if err != nil {
@@ -137,3 +217,44 @@ func TestPodLifecycle(t *testing.T) {
 		t.Fatalf("expected %d pods, provider knows about %d", desiredPodCount, currentPodCount)
 	}
 }
+
+// TestPodLifecycleGracefulDeletion extends TestPodLifecycle by asserting that a pod
+// is only removed from the API server once the provider has confirmed cleanup, rather
+// than disappearing the instant deletion is requested.
+func TestPodLifecycleGracefulDeletion(t *testing.T) {
+	pod, err := f.CreatePod(f.CreateDummyPodObjectWithPrefix("nginx-2-", "foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := f.DeletePod(pod.Namespace, pod.Name); err != nil && !apierrors.IsNotFound(err) {
+			t.Error(err)
+		}
+	}()
+
+	if err := f.WaitUntilPodReady(pod.Namespace, pod.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	deleteStart := time.Now()
+	if err := f.DeletePod(pod.Namespace, pod.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pod object must still exist immediately after delete is requested, rather
+	// than being force-removed before the provider has had a chance to confirm
+	// cleanup.
+	if _, err := f.KubeClient.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected pod %s/%s to still exist immediately after delete was requested: %v", pod.Namespace, pod.Name, err)
+	}
+
+	if err := f.WaitUntilPodDeleted(pod.Namespace, pod.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cleanup is polled no faster than once a second; a pod gone faster than that
+	// was force-deleted without waiting for the provider to confirm cleanup.
+	if elapsed := time.Since(deleteStart); elapsed < time.Second {
+		t.Fatalf("expected virtual-kubelet to wait for provider-confirmed cleanup, but the pod was gone after only %s", elapsed)
+	}
+}
@@ -0,0 +1,152 @@
+package vkubelet
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodAdmitter is consulted by createPod before a pod reaches provider.CreatePod.
+// Admitters run in order; the first rejection short-circuits the chain.
+type PodAdmitter interface {
+	// Admit reports whether pod may proceed to the provider. When admit is false,
+	// reason is a CamelCase identifier suitable for PodStatus.Reason and message is a
+	// human-readable explanation suitable for PodStatus.Message.
+	Admit(ctx context.Context, pod *corev1.Pod) (admit bool, reason, message string)
+}
+
+// runAdmitters runs admitters in order and returns the first rejection, if any.
+func runAdmitters(ctx context.Context, admitters []PodAdmitter, pod *corev1.Pod) (reason, message string, ok bool) {
+	for _, a := range admitters {
+		if admit, reason, message := a.Admit(ctx, pod); !admit {
+			return reason, message, false
+		}
+	}
+	return "", "", true
+}
+
+// ensureDefaultAdmitters populates s.admitters with the built-in chain
+// (duplicate-name, unsupported-feature, resource-sanity) the first time createPod
+// runs for this Server, unless the caller already configured a chain explicitly. It's
+// guarded by s.admittersOnce so concurrent createPod calls can't race to build it
+// twice.
+func (s *Server) ensureDefaultAdmitters() {
+	s.admittersOnce.Do(func() {
+		if s.admitters == nil {
+			s.admitters = s.defaultPodAdmitters()
+		}
+	})
+}
+
+// defaultPodAdmitters builds the built-in admission chain wired to this Server's
+// provider and resource manager.
+func (s *Server) defaultPodAdmitters() []PodAdmitter {
+	return []PodAdmitter{
+		newDuplicatePodAdmitter(s.resourceManager.GetPods),
+		newUnsupportedFeatureAdmitter(s.provider),
+		newResourceSanityAdmitter(),
+	}
+}
+
+// Capabilities describes pod features a provider does not support. Providers declare
+// these via CapabilitiesProvider so the unsupported-feature admitter can reject such
+// pods up front, instead of every provider re-implementing the same validation inside
+// CreatePod.
+type Capabilities struct {
+	HostNetwork      bool
+	Privileged       bool
+	EphemeralVolumes bool
+}
+
+// CapabilitiesProvider is implemented by providers that want to declare pod features
+// they don't support.
+type CapabilitiesProvider interface {
+	Capabilities(ctx context.Context) Capabilities
+}
+
+// duplicatePodAdmitter rejects a pod whose namespace/name already belongs to another
+// known pod, so the provider is never asked to create the same workload twice.
+type duplicatePodAdmitter struct {
+	listPods func() []*corev1.Pod
+}
+
+func newDuplicatePodAdmitter(listPods func() []*corev1.Pod) PodAdmitter {
+	return &duplicatePodAdmitter{listPods: listPods}
+}
+
+func (a *duplicatePodAdmitter) Admit(ctx context.Context, pod *corev1.Pod) (bool, string, string) {
+	for _, p := range a.listPods() {
+		if p.UID == pod.UID {
+			continue
+		}
+		if p.Namespace == pod.Namespace && p.Name == pod.Name {
+			return false, "DuplicatePod", fmt.Sprintf("a pod named %q already exists in namespace %q", pod.Name, pod.Namespace)
+		}
+	}
+	return true, "", ""
+}
+
+// unsupportedFeatureAdmitter rejects pods that use a feature the provider has declared
+// it doesn't support via CapabilitiesProvider.
+type unsupportedFeatureAdmitter struct {
+	provider interface{}
+}
+
+func newUnsupportedFeatureAdmitter(provider interface{}) PodAdmitter {
+	return &unsupportedFeatureAdmitter{provider: provider}
+}
+
+func (a *unsupportedFeatureAdmitter) Admit(ctx context.Context, pod *corev1.Pod) (bool, string, string) {
+	cp, ok := a.provider.(CapabilitiesProvider)
+	if !ok {
+		return true, "", ""
+	}
+	caps := cp.Capabilities(ctx)
+
+	if caps.HostNetwork && pod.Spec.HostNetwork {
+		return false, "UnsupportedHostNetwork", "the provider does not support hostNetwork pods"
+	}
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	if caps.Privileged {
+		for _, c := range containers {
+			if c.SecurityContext != nil && c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
+				return false, "UnsupportedPrivileged", fmt.Sprintf("the provider does not support privileged containers (container %q)", c.Name)
+			}
+		}
+	}
+
+	if caps.EphemeralVolumes {
+		for _, v := range pod.Spec.Volumes {
+			if v.EmptyDir != nil {
+				return false, "UnsupportedEphemeralVolume", fmt.Sprintf("the provider does not support ephemeral volumes (volume %q)", v.Name)
+			}
+		}
+	}
+
+	return true, "", ""
+}
+
+// resourceSanityAdmitter rejects pods whose container resource requests exceed their
+// own limits, catching malformed specs before they reach the provider.
+type resourceSanityAdmitter struct{}
+
+func newResourceSanityAdmitter() PodAdmitter {
+	return &resourceSanityAdmitter{}
+}
+
+func (a *resourceSanityAdmitter) Admit(ctx context.Context, pod *corev1.Pod) (bool, string, string) {
+	for _, c := range pod.Spec.Containers {
+		for name, request := range c.Resources.Requests {
+			limit, ok := c.Resources.Limits[name]
+			if !ok {
+				continue
+			}
+			if request.Cmp(limit) > 0 {
+				return false, "InvalidResourceRequest", fmt.Sprintf("container %q requests more %s (%s) than its limit (%s)", c.Name, name, request.String(), limit.String())
+			}
+		}
+	}
+	return true, "", ""
+}
@@ -0,0 +1,89 @@
+package vkubelet
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestDuplicatePodAdmitterRejectsSameNamespacedName(t *testing.T) {
+	existing := &corev1.Pod{}
+	existing.Namespace = "default"
+	existing.Name = "nginx"
+	existing.UID = "existing-uid"
+
+	admitter := newDuplicatePodAdmitter(func() []*corev1.Pod { return []*corev1.Pod{existing} })
+
+	pod := &corev1.Pod{}
+	pod.Namespace = "default"
+	pod.Name = "nginx"
+	pod.UID = "new-uid"
+
+	if admit, reason, _ := admitter.Admit(context.Background(), pod); admit || reason != "DuplicatePod" {
+		t.Fatalf("expected rejection with reason DuplicatePod, got admit=%v reason=%q", admit, reason)
+	}
+}
+
+func TestDuplicatePodAdmitterIgnoresItself(t *testing.T) {
+	pod := &corev1.Pod{}
+	pod.Namespace = "default"
+	pod.Name = "nginx"
+	pod.UID = "same-uid"
+
+	admitter := newDuplicatePodAdmitter(func() []*corev1.Pod { return []*corev1.Pod{pod} })
+
+	if admit, _, _ := admitter.Admit(context.Background(), pod); !admit {
+		t.Fatal("expected a pod to be admitted against its own existing record")
+	}
+}
+
+type fakeCapabilitiesProvider struct {
+	caps Capabilities
+}
+
+func (f *fakeCapabilitiesProvider) Capabilities(ctx context.Context) Capabilities {
+	return f.caps
+}
+
+func TestUnsupportedFeatureAdmitterRejectsHostNetwork(t *testing.T) {
+	admitter := newUnsupportedFeatureAdmitter(&fakeCapabilitiesProvider{caps: Capabilities{HostNetwork: true}})
+
+	pod := &corev1.Pod{}
+	pod.Spec.HostNetwork = true
+
+	if admit, reason, _ := admitter.Admit(context.Background(), pod); admit || reason != "UnsupportedHostNetwork" {
+		t.Fatalf("expected rejection with reason UnsupportedHostNetwork, got admit=%v reason=%q", admit, reason)
+	}
+}
+
+func TestUnsupportedFeatureAdmitterAllowsWhenProviderDoesntDeclareCapabilities(t *testing.T) {
+	admitter := newUnsupportedFeatureAdmitter(struct{}{})
+
+	pod := &corev1.Pod{}
+	pod.Spec.HostNetwork = true
+
+	if admit, _, _ := admitter.Admit(context.Background(), pod); !admit {
+		t.Fatal("expected admission when the provider doesn't implement CapabilitiesProvider")
+	}
+}
+
+func TestResourceSanityAdmitterRejectsRequestsAboveLimits(t *testing.T) {
+	admitter := newResourceSanityAdmitter()
+
+	pod := &corev1.Pod{}
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Name: "main",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+	}
+
+	if admit, reason, _ := admitter.Admit(context.Background(), pod); admit || reason != "InvalidResourceRequest" {
+		t.Fatalf("expected rejection with reason InvalidResourceRequest, got admit=%v reason=%q", admit, reason)
+	}
+}
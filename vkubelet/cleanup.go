@@ -0,0 +1,79 @@
+package vkubelet
+
+import (
+	"context"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/virtual-kubelet/virtual-kubelet/log"
+)
+
+// PodCleanedUp is implemented by providers whose DeletePod only starts asynchronous
+// teardown of the resources backing a pod. When a provider implements this, deletePod
+// polls it before removing the pod object from the Kubernetes API server, instead of
+// deleting the object out from under an in-flight teardown.
+type PodCleanedUp interface {
+	// PodCleanedUp reports whether the provider has finished releasing the resources
+	// backing the pod identified by namespace/name.
+	PodCleanedUp(ctx context.Context, namespace, name string) (bool, error)
+}
+
+const (
+	// cleanupPollInterval is the initial delay between PodCleanedUp polls.
+	cleanupPollInterval = 1 * time.Second
+	// cleanupPollIntervalCap bounds the exponential backoff between polls.
+	cleanupPollIntervalCap = 10 * time.Second
+	// defaultCleanupTimeout is used when the pod has no DeletionGracePeriodSeconds.
+	defaultCleanupTimeout = 30 * time.Second
+	// PodDisruptionReasonCleanupTimeout is recorded on the DisruptionTarget condition
+	// when the provider doesn't confirm cleanup before the pod's grace period elapses.
+	PodDisruptionReasonCleanupTimeout = "CleanupTimeout"
+)
+
+// waitForProviderCleanup polls the provider, if it implements PodCleanedUp, until it
+// reports the pod has been cleaned up, ctx is cancelled, or timeout elapses. Providers
+// that don't implement PodCleanedUp are assumed to have completed teardown
+// synchronously inside DeletePod, so this returns immediately.
+func (s *Server) waitForProviderCleanup(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	cleaner, ok := s.provider.(PodCleanedUp)
+	if !ok {
+		return nil
+	}
+
+	return pollPodCleanup(ctx, cleaner, namespace, name, timeout)
+}
+
+// pollPodCleanup repeatedly calls cleaner.PodCleanedUp with exponential backoff until
+// it reports the pod is gone, ctx is cancelled, or timeout elapses.
+func pollPodCleanup(ctx context.Context, cleaner PodCleanedUp, namespace, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backoff := wait.Backoff{
+		Duration: cleanupPollInterval,
+		Factor:   2,
+		Cap:      cleanupPollIntervalCap,
+		Steps:    math.MaxInt32,
+	}
+
+	return wait.ExponentialBackoffWithContext(ctx, backoff, func() (bool, error) {
+		done, err := cleaner.PodCleanedUp(ctx, namespace, name)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("Error polling provider for pod cleanup, will retry")
+			return false, nil
+		}
+		return done, nil
+	})
+}
+
+// podCleanupTimeout returns how long to wait for the provider to confirm cleanup
+// before forcing deletion, based on the pod's own grace period.
+func podCleanupTimeout(pod *corev1.Pod) time.Duration {
+	if pod.DeletionGracePeriodSeconds == nil {
+		return defaultCleanupTimeout
+	}
+	return time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second
+}
@@ -0,0 +1,51 @@
+package vkubelet
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// delayedCleanupProvider implements PodCleanedUp and reports cleanup as done only
+// after a fixed number of polls, simulating a provider with async teardown.
+type delayedCleanupProvider struct {
+	pollsUntilDone int32
+	polls          int32
+}
+
+func (p *delayedCleanupProvider) PodCleanedUp(ctx context.Context, namespace, name string) (bool, error) {
+	n := atomic.AddInt32(&p.polls, 1)
+	return n >= p.pollsUntilDone, nil
+}
+
+func TestPollPodCleanupWaitsUntilConfirmed(t *testing.T) {
+	provider := &delayedCleanupProvider{pollsUntilDone: 2}
+
+	if err := pollPodCleanup(context.Background(), provider, "default", "nginx", 3*time.Second); err != nil {
+		t.Fatalf("expected cleanup to succeed, got error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&provider.polls); got < provider.pollsUntilDone {
+		t.Fatalf("expected at least %d polls, got %d", provider.pollsUntilDone, got)
+	}
+}
+
+// TestPollPodCleanupTimesOut asserts that pollPodCleanup actually stops once timeout
+// elapses, rather than continuing until wait.Backoff's own step limit is exhausted
+// (which, with Steps set to math.MaxInt32 and polls at least a second apart, would in
+// practice never happen).
+func TestPollPodCleanupTimesOut(t *testing.T) {
+	provider := &delayedCleanupProvider{pollsUntilDone: 1000}
+
+	start := time.Now()
+	err := pollPodCleanup(context.Background(), provider, "default", "nginx", 10*time.Millisecond)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected pollPodCleanup to time out, got nil error")
+	}
+	if elapsed > time.Second {
+		t.Fatalf("expected pollPodCleanup to respect the 10ms timeout, took %s", elapsed)
+	}
+}
@@ -0,0 +1,37 @@
+package vkubelet
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Event reasons recorded against pods via the Server's record.EventRecorder at each
+// lifecycle transition, so that `kubectl describe pod` shows virtual-kubelet's
+// actions instead of going silent between the API server and the provider.
+const (
+	ReasonProviderCreated          = "ProviderCreated"
+	ReasonProviderCreateFailed     = "ProviderCreateFailed"
+	ReasonProviderDeleted          = "ProviderDeleted"
+	ReasonProviderDeleteFailed     = "ProviderDeleteFailed"
+	ReasonProviderStatusSyncFailed = "ProviderStatusSyncFailed"
+	ReasonProviderPodMissing       = "ProviderPodMissing"
+	ReasonPodAdmissionRejected     = "PodAdmissionRejected"
+)
+
+// recordPodEvent emits a normal event against pod via the Server's EventRecorder, if
+// one is configured. A nil recorder is a no-op so Server remains usable in tests and
+// commands that don't wire one up.
+func (s *Server) recordPodEvent(pod *corev1.Pod, reason, messageFmt string, args ...interface{}) {
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.Eventf(pod, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
+// recordPodWarningEvent emits a warning event against pod via the Server's
+// EventRecorder, if one is configured.
+func (s *Server) recordPodWarningEvent(pod *corev1.Pod, reason, messageFmt string, args ...interface{}) {
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.Eventf(pod, corev1.EventTypeWarning, reason, messageFmt, args...)
+}
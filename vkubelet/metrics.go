@@ -0,0 +1,126 @@
+package vkubelet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
+
+	"github.com/virtual-kubelet/virtual-kubelet/log"
+)
+
+// PrometheusMetricsProvider is implemented by providers that can export their own
+// per-pod/per-container Prometheus metric families directly, instead of relying on
+// the default conversion from the stats.Summary a PodMetricsProvider already returns
+// for /stats/summary.
+type PrometheusMetricsProvider interface {
+	GatherMetrics(ctx context.Context) ([]*dto.MetricFamily, error)
+}
+
+// RegisterResourceMetricsHandler registers the Prometheus resource metrics scrape
+// endpoint on mux, alongside the existing /stats/summary handler.
+func (s *Server) RegisterResourceMetricsHandler(mux *http.ServeMux) {
+	mux.HandleFunc("/metrics/resource", s.HandleResourceMetrics)
+}
+
+// HandleResourceMetrics serves per-pod/per-container resource metrics in Prometheus
+// exposition format, so autoscalers like Prometheus Adapter, KEDA, or custom HPAs can
+// scrape virtual-kubelet-backed pods the same way they would a real kubelet. The
+// families come from the provider's own PrometheusMetricsProvider implementation if
+// it has one, or else are converted from the stats.Summary it already exposes via
+// PodMetricsProvider, so providers get scraping "for free".
+func (s *Server) HandleResourceMetrics(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	families, err := s.gatherResourceMetrics(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", string(expfmt.FmtText))
+	enc := expfmt.NewEncoder(w, expfmt.FmtText)
+	for _, mf := range families {
+		if err := enc.Encode(mf); err != nil {
+			log.G(ctx).WithError(err).Warn("Failed to encode resource metric family")
+			return
+		}
+	}
+}
+
+func (s *Server) gatherResourceMetrics(ctx context.Context) ([]*dto.MetricFamily, error) {
+	if pmp, ok := s.provider.(PrometheusMetricsProvider); ok {
+		return pmp.GatherMetrics(ctx)
+	}
+
+	mp, ok := s.provider.(PodMetricsProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider does not implement PrometheusMetricsProvider or PodMetricsProvider")
+	}
+
+	summary, err := mp.GetStatsSummary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return summaryToMetricFamilies(summary), nil
+}
+
+// summaryToMetricFamilies converts a kubelet-shaped stats.Summary into the
+// container_cpu_usage_seconds_total, container_memory_working_set_bytes, and
+// pod_cpu_usage_seconds_total families, keyed by namespace/pod/container labels, that
+// a real kubelet would expose for cAdvisor-backed scraping.
+func summaryToMetricFamilies(summary *stats.Summary) []*dto.MetricFamily {
+	var containerCPU, containerMemory, podCPU []*dto.Metric
+
+	for _, pod := range summary.Pods {
+		var podCPUCoreSeconds float64
+
+		for _, c := range pod.Containers {
+			labels := []*dto.LabelPair{
+				labelPair("namespace", pod.PodRef.Namespace),
+				labelPair("pod", pod.PodRef.Name),
+				labelPair("container", c.Name),
+			}
+
+			if c.CPU != nil && c.CPU.UsageCoreNanoSeconds != nil {
+				coreSeconds := float64(*c.CPU.UsageCoreNanoSeconds) / 1e9
+				podCPUCoreSeconds += coreSeconds
+				containerCPU = append(containerCPU, counterMetric(labels, coreSeconds))
+			}
+			if c.Memory != nil && c.Memory.WorkingSetBytes != nil {
+				containerMemory = append(containerMemory, gaugeMetric(labels, float64(*c.Memory.WorkingSetBytes)))
+			}
+		}
+
+		podCPU = append(podCPU, counterMetric([]*dto.LabelPair{
+			labelPair("namespace", pod.PodRef.Namespace),
+			labelPair("pod", pod.PodRef.Name),
+		}, podCPUCoreSeconds))
+	}
+
+	return []*dto.MetricFamily{
+		metricFamily("container_cpu_usage_seconds_total", "Cumulative CPU time consumed by the container, in core-seconds", dto.MetricType_COUNTER, containerCPU),
+		metricFamily("container_memory_working_set_bytes", "Current working set of the container, in bytes", dto.MetricType_GAUGE, containerMemory),
+		metricFamily("pod_cpu_usage_seconds_total", "Cumulative CPU time consumed by all containers in the pod, in core-seconds", dto.MetricType_COUNTER, podCPU),
+	}
+}
+
+func labelPair(name, value string) *dto.LabelPair {
+	return &dto.LabelPair{Name: &name, Value: &value}
+}
+
+func counterMetric(labels []*dto.LabelPair, value float64) *dto.Metric {
+	return &dto.Metric{Label: labels, Counter: &dto.Counter{Value: &value}}
+}
+
+func gaugeMetric(labels []*dto.LabelPair, value float64) *dto.Metric {
+	return &dto.Metric{Label: labels, Gauge: &dto.Gauge{Value: &value}}
+}
+
+func metricFamily(name, help string, mtype dto.MetricType, metrics []*dto.Metric) *dto.MetricFamily {
+	return &dto.MetricFamily{Name: &name, Help: &help, Type: &mtype, Metric: metrics}
+}
@@ -0,0 +1,39 @@
+package vkubelet
+
+import (
+	"testing"
+
+	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
+)
+
+func uint64Ptr(v uint64) *uint64 { return &v }
+
+func TestSummaryToMetricFamiliesIncludesPerContainerSamples(t *testing.T) {
+	summary := &stats.Summary{
+		Pods: []stats.PodStats{
+			{
+				PodRef: stats.PodReference{Namespace: "default", Name: "nginx"},
+				Containers: []stats.ContainerStats{
+					{
+						Name:   "nginx",
+						CPU:    &stats.CPUStats{UsageCoreNanoSeconds: uint64Ptr(2_000_000_000)},
+						Memory: &stats.MemoryStats{WorkingSetBytes: uint64Ptr(1024)},
+					},
+				},
+			},
+		},
+	}
+
+	families := summaryToMetricFamilies(summary)
+
+	byName := make(map[string]int)
+	for _, mf := range families {
+		byName[mf.GetName()] = len(mf.Metric)
+	}
+
+	for _, name := range []string{"container_cpu_usage_seconds_total", "container_memory_working_set_bytes", "pod_cpu_usage_seconds_total"} {
+		if byName[name] != 1 {
+			t.Fatalf("expected exactly one sample for %s, got %d", name, byName[name])
+		}
+	}
+}
@@ -3,6 +3,7 @@ package vkubelet
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/cpuguy83/strongerrors/status/ocstatus"
@@ -11,10 +12,75 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/virtual-kubelet/virtual-kubelet/log"
 )
 
+// podConditionTypeDisruptionTarget is the DisruptionTarget pod condition type,
+// recorded when a pod is terminated for reasons other than the workload failing.
+const podConditionTypeDisruptionTarget corev1.PodConditionType = "DisruptionTarget"
+
+// Reasons recorded on the DisruptionTarget condition when the provider rejects a pod,
+// evicts it, or reports it missing.
+const (
+	PodDisruptionReasonProviderRejected = "ProviderRejected"
+	PodDisruptionReasonProviderEvicted  = "ProviderEvicted"
+	PodDisruptionReasonProviderPodGC    = "ProviderPodGC"
+)
+
+// PodDisruptor is implemented by providers that can explicitly classify why a pod was
+// disrupted. When a provider implements this, virtual-kubelet uses the reason it
+// reports instead of the fixed default for the call site: ProviderRejected when
+// CreatePod fails, ProviderEvicted when the pod is deleted, and ProviderPodGC when
+// GetPodStatus returns no status (err is nil in that case).
+type PodDisruptor interface {
+	// DisruptionReason returns one of the PodDisruptionReason* constants describing
+	// why the pod identified by namespace/name was disrupted, and ok=true if the
+	// provider has an opinion. It returns ok=false to defer to virtual-kubelet's
+	// default inference.
+	DisruptionReason(ctx context.Context, namespace, name string, err error) (reason string, ok bool)
+}
+
+// setDisruptionCondition sets (or updates) the DisruptionTarget condition on pod,
+// preserving LastTransitionTime when the condition's status hasn't changed.
+func setDisruptionCondition(pod *corev1.Pod, reason, message string) {
+	now := metav1.NewTime(time.Now())
+	for i := range pod.Status.Conditions {
+		c := &pod.Status.Conditions[i]
+		if c.Type != podConditionTypeDisruptionTarget {
+			continue
+		}
+		if c.Status != corev1.ConditionTrue {
+			c.LastTransitionTime = now
+		}
+		c.Status = corev1.ConditionTrue
+		c.Reason = reason
+		c.Message = message
+		return
+	}
+
+	pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+		Type:               podConditionTypeDisruptionTarget,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// disruptionReason asks the provider for an explicit disruption reason via
+// PodDisruptor, falling back to defaultReason when the provider doesn't implement
+// the interface or has no opinion.
+func disruptionReason(ctx context.Context, provider interface{}, namespace, name string, err error, defaultReason string) string {
+	if d, ok := provider.(PodDisruptor); ok {
+		if reason, ok := d.DisruptionReason(ctx, namespace, name, err); ok {
+			return reason
+		}
+	}
+	return defaultReason
+}
+
 func addPodAttributes(span *trace.Span, pod *corev1.Pod) {
 	span.AddAttributes(
 		trace.StringAttribute("uid", string(pod.GetUID())),
@@ -30,13 +96,32 @@ func (s *Server) createPod(ctx context.Context, pod *corev1.Pod) error {
 	defer span.End()
 	addPodAttributes(span, pod)
 
+	logger := log.G(ctx).WithField("pod", pod.GetName()).WithField("namespace", pod.GetNamespace())
+
+	s.ensureDefaultAdmitters()
+	if reason, message, admitted := runAdmitters(ctx, s.admitters, pod); !admitted {
+		logger.WithField("reason", reason).WithField("message", message).Warn("Pod rejected by admission")
+
+		pod.ResourceVersion = "" // Blank out resource version to prevent object has been modified error
+		pod.Status.Phase = corev1.PodFailed
+		pod.Status.Reason = reason
+		pod.Status.Message = message
+
+		if _, err := s.k8sClient.CoreV1().Pods(pod.Namespace).UpdateStatus(pod); err != nil {
+			logger.WithError(err).Warn("Failed to update pod status")
+		}
+
+		s.recordPodWarningEvent(pod, ReasonPodAdmissionRejected, "Pod rejected by admission: %s", message)
+
+		span.SetStatus(trace.Status{Code: trace.StatusCodeInvalidArgument, Message: message})
+		return nil
+	}
+
 	if err := s.populateEnvironmentVariables(pod); err != nil {
 		span.SetStatus(trace.Status{Code: trace.StatusCodeInvalidArgument, Message: err.Error()})
 		return err
 	}
 
-	logger := log.G(ctx).WithField("pod", pod.GetName()).WithField("namespace", pod.GetNamespace())
-
 	if origErr := s.provider.CreatePod(ctx, pod); origErr != nil {
 		podPhase := corev1.PodPending
 		if pod.Spec.RestartPolicy == corev1.RestartPolicyNever {
@@ -48,6 +133,9 @@ func (s *Server) createPod(ctx context.Context, pod *corev1.Pod) error {
 		pod.Status.Reason = podStatusReasonProviderFailed
 		pod.Status.Message = origErr.Error()
 
+		reason := disruptionReason(ctx, s.provider, pod.Namespace, pod.Name, origErr, PodDisruptionReasonProviderRejected)
+		setDisruptionCondition(pod, reason, origErr.Error())
+
 		_, err := s.k8sClient.CoreV1().Pods(pod.Namespace).UpdateStatus(pod)
 		if err != nil {
 			logger.WithError(err).Warn("Failed to update pod status")
@@ -55,11 +143,14 @@ func (s *Server) createPod(ctx context.Context, pod *corev1.Pod) error {
 			span.Annotate(nil, "Updated k8s pod status")
 		}
 
+		s.recordPodWarningEvent(pod, ReasonProviderCreateFailed, "Failed to create pod in provider: %s", origErr.Error())
+
 		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: origErr.Error()})
 		return origErr
 	}
 	span.Annotate(nil, "Created pod in provider")
 
+	s.recordPodEvent(pod, ReasonProviderCreated, "Created pod in provider")
 	logger.Info("Pod created")
 
 	return nil
@@ -78,9 +169,35 @@ func (s *Server) deletePod(ctx context.Context, pod *corev1.Pod) error {
 	span.Annotate(nil, "Deleted pod from provider")
 
 	logger := log.G(ctx).WithField("pod", pod.GetName()).WithField("namespace", pod.GetNamespace())
+	if delErr != nil {
+		s.recordPodWarningEvent(pod, ReasonProviderDeleteFailed, "Failed to delete pod in provider: %s", delErr.Error())
+	}
 	if !errors.IsNotFound(delErr) {
-		var grace int64
-		if err := s.k8sClient.CoreV1().Pods(pod.GetNamespace()).Delete(pod.GetName(), &metav1.DeleteOptions{GracePeriodSeconds: &grace}); err != nil && errors.IsNotFound(err) {
+		// The provider's teardown (and any grace period the caller originally
+		// requested) has already run by the time we get here, so the finalizing
+		// delete always uses grace period 0 - a non-zero GracePeriodSeconds on an
+		// object that's already marked for deletion is a no-op to the apiserver.
+		grace := int64(0)
+
+		reason := disruptionReason(ctx, s.provider, pod.GetNamespace(), pod.GetName(), delErr, PodDisruptionReasonProviderEvicted)
+		setDisruptionCondition(pod, reason, "Pod removed by provider")
+		if _, uerr := s.k8sClient.CoreV1().Pods(pod.Namespace).UpdateStatus(pod); uerr != nil {
+			logger.WithError(uerr).Warn("Failed to record disruption condition")
+		}
+
+		if err := s.waitForProviderCleanup(ctx, pod.GetNamespace(), pod.GetName(), podCleanupTimeout(pod)); err != nil {
+			logger.WithError(err).Warn("Timed out waiting for provider to confirm pod cleanup, forcing deletion")
+			span.Annotate(nil, "Timed out waiting for provider cleanup")
+
+			setDisruptionCondition(pod, PodDisruptionReasonCleanupTimeout, err.Error())
+			if _, uerr := s.k8sClient.CoreV1().Pods(pod.Namespace).UpdateStatus(pod); uerr != nil {
+				logger.WithError(uerr).Warn("Failed to record cleanup timeout condition")
+			}
+		} else {
+			span.Annotate(nil, "Provider confirmed pod cleanup")
+		}
+
+		if err := s.k8sClient.CoreV1().Pods(pod.GetNamespace()).Delete(pod.GetName(), &metav1.DeleteOptions{GracePeriodSeconds: &grace}); err != nil {
 			if errors.IsNotFound(err) {
 				span.Annotate(nil, "Pod does not exist in k8s, nothing to delete")
 				return nil
@@ -90,18 +207,24 @@ func (s *Server) deletePod(ctx context.Context, pod *corev1.Pod) error {
 			return fmt.Errorf("Failed to delete kubernetes pod: %s", err)
 		}
 		span.Annotate(nil, "Deleted pod from k8s")
+		if delErr == nil {
+			s.recordPodEvent(pod, ReasonProviderDeleted, "Deleted pod from provider")
+		}
 		logger.Info("Pod deleted")
 	}
 
 	return nil
 }
 
-// updatePodStatuses syncs the providers pod status with the kubernetes pod status.
+// updatePodStatuses enqueues every known pod for a status sync with the provider
+// instead of syncing them serially. The status manager's work queue dedups the
+// resulting provider and API server calls, runs a bounded number of them in parallel,
+// and rate-limits retries of failed syncs; every known pod is still synced once per
+// tick.
 func (s *Server) updatePodStatuses(ctx context.Context) {
 	ctx, span := trace.StartSpan(ctx, "updatePodStatuses")
 	defer span.End()
 
-	// Update all the pods with the provider status.
 	pods := s.resourceManager.GetPods()
 	span.AddAttributes(trace.Int64Attribute("nPods", int64(len(pods))))
 
@@ -113,13 +236,21 @@ func (s *Server) updatePodStatuses(ctx context.Context) {
 		default:
 		}
 
-		if err := s.updatePodStatus(ctx, pod); err != nil {
-			logger := log.G(ctx).WithField("pod", pod.GetName()).WithField("namespace", pod.GetNamespace()).WithField("status", pod.Status.Phase).WithField("reason", pod.Status.Reason)
-			logger.Error(err)
-		}
+		s.SyncPodStatus(ctx, pod)
 	}
 }
 
+// SyncPodStatus enqueues pod to have its status synced with the provider. It's the
+// single entry point for requesting a status sync, whether from the periodic
+// updatePodStatuses tick, pod create/delete, or informer event handlers; duplicate
+// and superseded requests for the same pod are coalesced by the status manager. The
+// status manager's worker goroutines are started, bound to ctx's lifetime, the first
+// time this is called.
+func (s *Server) SyncPodStatus(ctx context.Context, pod *corev1.Pod) {
+	s.statusManager.ensureStarted(ctx)
+	s.statusManager.enqueue(pod)
+}
+
 func (s *Server) updatePodStatus(ctx context.Context, pod *corev1.Pod) error {
 	ctx, span := trace.StartSpan(ctx, "updatePodStatus")
 	defer span.End()
@@ -131,14 +262,22 @@ func (s *Server) updatePodStatus(ctx context.Context, pod *corev1.Pod) error {
 		return nil
 	}
 
+	oldPod := pod.DeepCopy()
+
 	status, err := s.provider.GetPodStatus(ctx, pod.Namespace, pod.Name)
 	if err != nil {
 		span.SetStatus(ocstatus.FromError(err))
+		s.recordPodWarningEvent(pod, ReasonProviderStatusSyncFailed, "Failed to sync pod status from provider: %s", err.Error())
 		return pkgerrors.Wrap(err, "error retreiving pod status")
 	}
 
 	// Update the pod's status
 	if status != nil {
+		// StartTime isn't something providers are expected to track across calls, so
+		// carry it forward rather than losing it on every sync.
+		if status.StartTime == nil {
+			status.StartTime = oldPod.Status.StartTime
+		}
 		pod.Status = *status
 	} else {
 		// Only change the status when the pod was already up
@@ -148,6 +287,11 @@ func (s *Server) updatePodStatus(ctx context.Context, pod *corev1.Pod) error {
 			pod.Status.Phase = corev1.PodFailed
 			pod.Status.Reason = "NotFound"
 			pod.Status.Message = "The pod status was not found and may have been deleted from the provider"
+
+			reason := disruptionReason(ctx, s.provider, pod.Namespace, pod.Name, nil, PodDisruptionReasonProviderPodGC)
+			setDisruptionCondition(pod, reason, pod.Status.Message)
+			s.recordPodWarningEvent(pod, ReasonProviderPodMissing, pod.Status.Message)
+
 			for i, c := range pod.Status.ContainerStatuses {
 				pod.Status.ContainerStatuses[i].State.Terminated = &corev1.ContainerStateTerminated{
 					ExitCode:    -137,
@@ -162,7 +306,19 @@ func (s *Server) updatePodStatus(ctx context.Context, pod *corev1.Pod) error {
 		}
 	}
 
-	if _, err := s.k8sClient.CoreV1().Pods(pod.Namespace).UpdateStatus(pod); err != nil {
+	if reflect.DeepEqual(oldPod.Status, pod.Status) {
+		span.Annotate(nil, "Computed pod status matches last-sent status, skipping patch")
+		recordDroppedStatusSync(ctx)
+		return nil
+	}
+
+	patchBytes, err := preparePodStatusPatch(oldPod, pod)
+	if err != nil {
+		span.SetStatus(ocstatus.FromError(err))
+		return pkgerrors.Wrap(err, "error building pod status patch")
+	}
+
+	if _, err := s.k8sClient.CoreV1().Pods(pod.Namespace).Patch(pod.Name, types.StrategicMergePatchType, patchBytes, "status"); err != nil {
 		span.SetStatus(ocstatus.FromError(err))
 		return pkgerrors.Wrap(err, "error while updating pod status in kubernetes")
 	}
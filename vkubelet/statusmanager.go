@@ -0,0 +1,185 @@
+package vkubelet
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/virtual-kubelet/virtual-kubelet/log"
+)
+
+// defaultStatusSyncConcurrency bounds how many pod status syncs run against the
+// provider and the API server at once.
+const defaultStatusSyncConcurrency = 10
+
+var (
+	mStatusQueueDepth     = stats.Int64("virtual_kubelet/pod_status_queue_depth", "Number of pod status sync requests currently queued", "1")
+	mStatusSyncLatencyMs  = stats.Float64("virtual_kubelet/pod_status_sync_latency_ms", "Time taken to sync a single pod's status", "ms")
+	mStatusDroppedUpdates = stats.Int64("virtual_kubelet/pod_status_dropped_updates", "Number of pod status syncs skipped because the computed status matched the last-sent status", "1")
+)
+
+func init() {
+	if err := view.Register(
+		&view.View{
+			Name:        "virtual_kubelet/pod_status_queue_depth",
+			Measure:     mStatusQueueDepth,
+			Description: "Number of pod status sync requests currently queued",
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Name:        "virtual_kubelet/pod_status_sync_latency_ms",
+			Measure:     mStatusSyncLatencyMs,
+			Description: "Distribution of pod status sync latencies",
+			Aggregation: view.Distribution(1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000),
+		},
+		&view.View{
+			Name:        "virtual_kubelet/pod_status_dropped_updates",
+			Measure:     mStatusDroppedUpdates,
+			Description: "Number of pod status syncs skipped as no-ops",
+			Aggregation: view.Count(),
+		},
+	); err != nil {
+		panic(err)
+	}
+}
+
+// statusManager batches pod status syncs to the Kubernetes API server. Sync requests
+// are deduped by pod UID via a rate-limiting work queue: updates for the same pod
+// that arrive before a worker picks them up are coalesced into one sync of the pod's
+// current status. At most concurrency syncs run at once. Only failed syncs are
+// requeued through the queue's rate limiter; newly enqueued syncs are not delayed by
+// it. Whether a sync is a no-op is decided in
+// updatePodStatus against a freshly-fetched provider status, not cached here.
+type statusManager struct {
+	server *Server
+	queue  workqueue.RateLimitingInterface
+
+	mu      sync.Mutex
+	pending map[types.UID]*corev1.Pod
+
+	concurrency int
+	startOnce   sync.Once
+
+	// syncPodStatus performs the actual sync for a pending pod. It's a field rather
+	// than a direct call to server.updatePodStatus so tests can exercise sync's
+	// retry behavior without a real Server.
+	syncPodStatus func(ctx context.Context, pod *corev1.Pod) error
+}
+
+func newStatusManager(s *Server) *statusManager {
+	return &statusManager{
+		server:        s,
+		queue:         workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		pending:       make(map[types.UID]*corev1.Pod),
+		concurrency:   defaultStatusSyncConcurrency,
+		syncPodStatus: s.updatePodStatus,
+	}
+}
+
+// enqueue schedules pod's status to be synced, coalescing with any sync already
+// pending for the same UID into a single sync of the latest status.
+func (m *statusManager) enqueue(pod *corev1.Pod) {
+	m.mu.Lock()
+	m.pending[pod.UID] = pod
+	m.mu.Unlock()
+
+	m.queue.Add(pod.UID)
+	stats.Record(context.Background(), mStatusQueueDepth.M(int64(m.queue.Len())))
+}
+
+// ensureStarted starts the configured number of worker goroutines the first time
+// it's called, bound to ctx's lifetime. Later calls are no-ops, so it's safe to call
+// on every enqueue.
+func (m *statusManager) ensureStarted(ctx context.Context) {
+	m.startOnce.Do(func() {
+		go m.run(ctx)
+	})
+}
+
+// run starts the configured number of worker goroutines and blocks until ctx is done.
+func (m *statusManager) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < m.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.worker(ctx)
+		}()
+	}
+	<-ctx.Done()
+	m.queue.ShutDown()
+	wg.Wait()
+}
+
+func (m *statusManager) worker(ctx context.Context) {
+	for {
+		key, shutdown := m.queue.Get()
+		if shutdown {
+			return
+		}
+
+		m.sync(ctx, key.(types.UID))
+		m.queue.Done(key)
+		stats.Record(ctx, mStatusQueueDepth.M(int64(m.queue.Len())))
+	}
+}
+
+func (m *statusManager) sync(ctx context.Context, uid types.UID) {
+	m.mu.Lock()
+	pod, ok := m.pending[uid]
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	logger := log.G(ctx).WithField("pod", pod.GetName()).WithField("namespace", pod.GetNamespace())
+
+	start := time.Now()
+	err := m.syncPodStatus(ctx, pod)
+	stats.Record(ctx, mStatusSyncLatencyMs.M(float64(time.Since(start))/float64(time.Millisecond)))
+	if err != nil {
+		logger.WithError(err).Error("Failed to sync pod status")
+		m.queue.AddRateLimited(uid)
+		return
+	}
+
+	// Only clear pending if it still holds the pod we just synced - a newer update
+	// may have been coalesced into it while the sync was in flight.
+	m.mu.Lock()
+	if m.pending[uid] == pod {
+		delete(m.pending, uid)
+	}
+	m.mu.Unlock()
+
+	m.queue.Forget(uid)
+}
+
+// recordDroppedStatusSync records that a status sync was skipped because the status
+// freshly computed from the provider matched what was already sent to the API
+// server. Called from updatePodStatus, which is the only place with access to both
+// the old and newly-fetched status.
+func recordDroppedStatusSync(ctx context.Context) {
+	stats.Record(ctx, mStatusDroppedUpdates.M(1))
+}
+
+// preparePodStatusPatch computes a strategic merge patch containing only the status
+// fields that changed between oldPod and newPod.
+func preparePodStatusPatch(oldPod, newPod *corev1.Pod) ([]byte, error) {
+	oldData, err := json.Marshal(corev1.Pod{Status: oldPod.Status})
+	if err != nil {
+		return nil, err
+	}
+	newData, err := json.Marshal(corev1.Pod{Status: newPod.Status})
+	if err != nil {
+		return nil, err
+	}
+	return strategicpatch.CreateTwoWayMergePatch(oldData, newData, corev1.Pod{})
+}
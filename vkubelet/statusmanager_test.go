@@ -0,0 +1,141 @@
+package vkubelet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestStatusManagerEnsureStartedIsIdempotent(t *testing.T) {
+	m := newStatusManager(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Repeated calls must not spawn a second set of workers against the same queue.
+	for i := 0; i < 3; i++ {
+		m.ensureStarted(ctx)
+	}
+}
+
+// TestStatusManagerEnqueueAlwaysQueuesSync guards against reintroducing an
+// enqueue-time cache keyed off the live pod object: the pod for a given UID is the same
+// resourceManager-owned *corev1.Pod across every periodic tick, so a pre-filter that
+// compares it against a "last synced" snapshot of itself can never detect drift after
+// the first sync. The no-op check belongs in updatePodStatus, where it's compared
+// against a freshly-fetched provider status instead.
+func TestStatusManagerEnqueueAlwaysQueuesSync(t *testing.T) {
+	m := newStatusManager(nil)
+
+	pod := &corev1.Pod{}
+	pod.UID = types.UID("abc")
+	pod.Status.Phase = corev1.PodRunning
+
+	// Simulate repeated ticks over many periods where the pod's status hasn't
+	// changed between enqueue calls (the realistic, common case).
+	for i := 0; i < 5; i++ {
+		m.enqueue(pod)
+	}
+
+	if _, pending := m.pending[pod.UID]; !pending {
+		t.Fatal("expected the pod to remain queued for a sync regardless of prior enqueues")
+	}
+	if m.queue.Len() != 1 {
+		t.Fatalf("expected a single coalesced queue entry, got queue length %d", m.queue.Len())
+	}
+}
+
+func TestStatusManagerEnqueueCoalescesPendingUpdates(t *testing.T) {
+	m := newStatusManager(nil)
+
+	pod := &corev1.Pod{}
+	pod.UID = types.UID("abc")
+	pod.Status.Phase = corev1.PodPending
+
+	m.enqueue(pod)
+	pod.Status.Phase = corev1.PodRunning
+	m.enqueue(pod)
+
+	if got := m.pending[pod.UID].Status.Phase; got != corev1.PodRunning {
+		t.Fatalf("expected coalesced update to carry the latest status, got %s", got)
+	}
+	if m.queue.Len() != 1 {
+		t.Fatalf("expected a single queued sync for repeated updates to the same pod, got %d", m.queue.Len())
+	}
+}
+
+// TestStatusManagerSyncRetriesFailedSyncWithPodData guards against dropping a pod
+// from pending before its sync has actually succeeded: if sync cleared pending
+// unconditionally, a later retry popped off the rate limiter would find nothing
+// pending for that UID and silently no-op instead of retrying.
+func TestStatusManagerSyncRetriesFailedSyncWithPodData(t *testing.T) {
+	m := newStatusManager(nil)
+
+	pod := &corev1.Pod{}
+	pod.UID = types.UID("abc")
+	pod.Status.Phase = corev1.PodRunning
+
+	calls := 0
+	m.syncPodStatus = func(ctx context.Context, p *corev1.Pod) error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("transient provider error")
+		}
+		if p != pod {
+			t.Fatalf("expected retry to sync the original pod, got a different object")
+		}
+		return nil
+	}
+
+	m.enqueue(pod)
+	m.sync(context.Background(), pod.UID)
+	if calls != 1 {
+		t.Fatalf("expected exactly one sync attempt, got %d", calls)
+	}
+	if _, pending := m.pending[pod.UID]; !pending {
+		t.Fatal("expected the pod to remain pending after a failed sync so it can be retried")
+	}
+
+	m.sync(context.Background(), pod.UID)
+	if calls != 2 {
+		t.Fatalf("expected a second sync attempt on retry, got %d", calls)
+	}
+	if _, pending := m.pending[pod.UID]; pending {
+		t.Fatal("expected the pod to be cleared from pending after a successful sync")
+	}
+}
+
+func TestPreparePodStatusPatchOnlyIncludesStatus(t *testing.T) {
+	oldPod := &corev1.Pod{}
+	oldPod.Status.Phase = corev1.PodPending
+
+	newPod := &corev1.Pod{}
+	newPod.Status.Phase = corev1.PodRunning
+	newPod.Status.Reason = "started"
+
+	patch, err := preparePodStatusPatch(oldPod, newPod)
+	if err != nil {
+		t.Fatalf("unexpected error building patch: %v", err)
+	}
+	if len(patch) == 0 {
+		t.Fatal("expected a non-empty patch for a changed status")
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		t.Fatalf("unexpected error decoding patch: %v", err)
+	}
+	if _, ok := fields["status"]; !ok || len(fields) != 1 {
+		t.Fatalf("expected a patch with only a status field, got %s", patch)
+	}
+
+	noopPatch, err := preparePodStatusPatch(oldPod, oldPod)
+	if err != nil {
+		t.Fatalf("unexpected error building no-op patch: %v", err)
+	}
+	if string(noopPatch) != "{}" {
+		t.Fatalf("expected an empty patch for an unchanged status, got %s", noopPatch)
+	}
+}